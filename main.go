@@ -1,25 +1,52 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	monitorclient "github.com/xpadev/k8s-node-monitor/pkg/client"
 	"github.com/xpadev/k8s-node-monitor/pkg/config"
-	"github.com/xpadev/k8s-node-monitor/pkg/discord"
+	"github.com/xpadev/k8s-node-monitor/pkg/controller"
+	"github.com/xpadev/k8s-node-monitor/pkg/httpserver"
 	"github.com/xpadev/k8s-node-monitor/pkg/k8s"
+	"github.com/xpadev/k8s-node-monitor/pkg/metrics"
+	"github.com/xpadev/k8s-node-monitor/pkg/notify"
 	"github.com/xpadev/k8s-node-monitor/pkg/proxmox"
 )
 
 const DEFAULT_CONFIG_PATH = "config.yaml"
 
+// workerCount はworkqueueを処理するワーカーgoroutineの数です
+const workerCount = 2
+
 func main() {
 	// コマンドライン引数の処理
 	configPath := flag.String("config", DEFAULT_CONFIG_PATH, "設定ファイルのパス")
 	enableRestart := flag.Bool("restart", false, "NotReadyノードの自動再起動を有効にする")
+	metricsBindAddress := flag.String("metrics-bind-address", ":8080", "/healthz, /readyz, /metricsを公開するアドレス")
+	enableProfiling := flag.Bool("enable-profiling", false, "/debug/pprof/*エンドポイントを有効にする")
+	leaderElect := flag.Bool("leader-elect", false, "複数レプリカで安全に動かすためのリーダー選出を有効にする")
+	leaderElectionNamespace := flag.String("leader-election-namespace", "default", "リーダー選出に使うLeaseを置くNamespace")
+	leaderElectionLeaseName := flag.String("leader-election-lease-name", "k8s-node-monitor-leader", "リーダー選出に使うLeaseの名前")
+	leaseDuration := flag.Duration("leader-election-lease-duration", 15*time.Second, "他のレプリカがリーダーを引き継げるまでの非アクティブ許容時間")
+	renewDeadline := flag.Duration("leader-election-renew-deadline", 10*time.Second, "リーダーがリースを更新し続けられる最大時間")
+	retryPeriod := flag.Duration("leader-election-retry-period", 2*time.Second, "リーダー候補がリース取得を再試行する間隔")
+	restartRecoveryTimeout := flag.Duration("restart-recovery-timeout", 5*time.Minute, "再起動後にノードがReadyへ復帰するのを待つ最大時間")
 	flag.Parse()
 
+	// SIGTERM/SIGINTでキャンセルされるコンテキストを用意する
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// 設定ファイルの読み込み
 	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
@@ -28,129 +55,89 @@ func main() {
 
 	// ProxmoxクライアントとK8sクライアントの作成
 	proxmoxClient := proxmox.NewClient(&cfg.Proxmox)
+	defer proxmoxClient.Close()
 	k8sClient, err := k8s.NewClient()
 	if err != nil {
 		log.Fatalf("K8sクライアント作成エラー: %v", err)
 	}
 
-	// Discord Webhookクライアントの作成
-	discordClient := discord.NewWebhookClient(cfg.Discord.WebhookURL, cfg.Discord.Enabled)
-
-	// ノードリストの取得
-	nodes, err := k8sClient.GetNodes()
+	// 設定された通知シンク(Discord/Slack/Teams/PagerDuty/Webhook)からMultiNotifierを組み立てる
+	sinks, err := notify.BuildSinks(cfg.Sinks)
 	if err != nil {
-		log.Fatalf("ノード取得エラー: %v", err)
+		log.Fatalf("通知シンク作成エラー: %v", err)
 	}
+	notifier := notify.NewMulti(sinks...)
 
-	// ノード情報の表示
-	fmt.Println("Kubernetes Cluster Nodes:")
-	fmt.Println("=========================")
-
-	for _, node := range nodes {
-		displayNodeInfo(node, cfg, proxmoxClient, discordClient, *enableRestart)
-	}
-}
-
-// displayNodeInfo はノード情報を表示し、必要に応じて再起動処理を行います
-func displayNodeInfo(node k8s.NodeInfo, cfg *config.Config, proxmoxClient *proxmox.Client, discordClient *discord.WebhookClient, enableRestart bool) {
-	fmt.Printf("Name: %s\n", node.Name)
-
-	// Readyの場合は単純に表示して終了
-	if node.Status == "Ready" {
-		fmt.Printf("  Status: %s\n", node.Status)
-	} else {
-		// NotReadyの場合、期間も表示
-		fmt.Printf("  Status: %s (for %s)\n", node.Status, node.NotReadyDuration)
-		
-		// 自動再起動が有効でない場合は再起動処理をスキップ
-		if !enableRestart {
-			fmt.Printf("  Action: Automatic restart disabled\n")
-			
-			// Discord通知（再起動なし）
-			notifyDiscord(node, cfg, discordClient, false)
-		} else if time.Since(node.LastTransition).Minutes() <= 1 {
-			// 1分未満の場合は再起動しない
-			fmt.Printf("  Action: Node is NotReady but for less than 1 minute, no restart needed\n")
-			
-			// Discord通知（再起動なし）
-			notifyDiscord(node, cfg, discordClient, false)
-		} else {
-			// 1分以上NotReadyなので再起動処理
-			handleNodeRestart(node, cfg, proxmoxClient, discordClient)
-		}
+	// NodeRestartPolicy CRD用の型付きクライアントの作成
+	policyClient, err := monitorclient.NewForConfig(k8sClient.RESTConfig())
+	if err != nil {
+		log.Fatalf("NodeRestartPolicyクライアント作成エラー: %v", err)
 	}
 
-	// 共通情報の表示
-	fmt.Printf("  IP: %s\n", node.IP)
-	fmt.Printf("  Kubelet Version: %s\n", node.KubeletVersion)
-	fmt.Printf("  OS/Arch: %s/%s\n", node.OSImage, node.Architecture)
-	fmt.Printf("  Allocatable Resources:\n")
-	fmt.Printf("    CPU: %s\n", node.AllocatableCPU)
-	fmt.Printf("    Memory: %s\n", node.AllocatableMemory)
-	fmt.Printf("    Pods: %s\n", node.AllocatablePods)
-	fmt.Println()
-}
+	ctrl := controller.New(k8sClient.Clientset(), policyClient, proxmoxClient, notifier, *enableRestart, *restartRecoveryTimeout)
 
-// notifyDiscord はDiscordにノード状態を通知します
-func notifyDiscord(node k8s.NodeInfo, cfg *config.Config, discordClient *discord.WebhookClient, isRestarting bool) {
-	if discordClient == nil || !discordClient.Enabled {
-		return
-	}
+	// Prometheusメトリクスを登録し、/healthz, /readyz, /metrics, (任意で)/debug/pprof/*を公開する
+	// informerのキャッシュ同期より前に起動することで、同期中も/healthzで生存確認でき、
+	// /readyz(ctrl.HasSynced)が同期完了までnot-readyを正しく返せるようにする
+	metrics.MustRegisterDefault()
+	server := httpserver.New(*metricsBindAddress, *enableProfiling, ctrl.HasSynced)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTPサーバーエラー: %v", err)
+		}
+	}()
 
-	vmInfo := ""
-	nodeMapping := cfg.FindNodeMapping(node.Name)
-	if nodeMapping != nil {
-		vmInfo = fmt.Sprintf("Proxmox Node: %s, VM ID: %d", nodeMapping.ProxmoxNode, nodeMapping.VMID)
+	// informerはリーダー選出の結果を待たず全レプリカで起動する。こうすることで
+	// フォロワーもキャッシュを温めた状態(ホットスタンバイ)でリーダー昇格できる
+	if err := ctrl.StartInformers(ctx.Done()); err != nil {
+		log.Fatalf("informer起動エラー: %v", err)
 	}
 
-	err := discordClient.SendNodeNotReadyNotification(node.Name, node.Status, node.NotReadyDuration, node.IP, vmInfo, isRestarting)
-	if err != nil {
-		fmt.Printf("  Discord通知エラー: %v\n", err)
-	} else {
-		fmt.Printf("  Discord通知: 送信成功\n")
+	runWorkers := func(ctx context.Context) {
+		// workqueueの処理(再起動のようなsideffectを伴う操作を含む)はここでのみ行う。
+		// ctxがキャンセルされるまでブロックする
+		ctrl.RunWorkers(workerCount, ctx.Done())
 	}
-}
 
-// handleNodeRestart はNotReadyノードの再起動処理を行います
-func handleNodeRestart(node k8s.NodeInfo, cfg *config.Config, proxmoxClient *proxmox.Client, discordClient *discord.WebhookClient) {
-	// 対応するProxmoxノードの情報を取得
-	nodeMapping := cfg.FindNodeMapping(node.Name)
-	if nodeMapping == nil {
-		fmt.Printf("  Action: No mapping found for node '%s' in config\n", node.Name)
-		
-		// Discord通知（再起動なし、マッピングなし）
-		notifyDiscord(node, cfg, discordClient, false)
+	if !*leaderElect {
+		runWorkers(ctx)
 		return
 	}
 
-	// まず状態を取得
-	status, err := proxmoxClient.GetVMStatus(nodeMapping.ProxmoxNode, nodeMapping.VMID)
+	identity, err := os.Hostname()
 	if err != nil {
-		fmt.Printf("  Status Error: VM状態取得失敗: %v\n", err)
-		
-		// Discord通知（再起動なし、エラー）
-		notifyDiscord(node, cfg, discordClient, false)
-		return
+		log.Fatalf("ホスト名取得エラー: %v", err)
 	}
-	
-	fmt.Printf("  Current VM Status: %s\n", status)
-	fmt.Printf("  Action: Restarting node via Proxmox (Node: %s, VMID: %d)\n", 
-		nodeMapping.ProxmoxNode, nodeMapping.VMID)
-
-	// Discord通知（再起動開始）
-	notifyDiscord(node, cfg, discordClient, true)
-	
-	// VMの再起動
-	err = proxmoxClient.RestartVM(nodeMapping.ProxmoxNode, nodeMapping.VMID)
+
+	// リーダーの間だけworkqueueを処理し、再起動のようなsideffectを伴う操作を行う。
+	// フォロワーもinformerは動かし続けるホットスタンバイであり、workqueueの処理だけを止める
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		*leaderElectionNamespace,
+		*leaderElectionLeaseName,
+		k8sClient.Clientset().CoreV1(),
+		k8sClient.Clientset().CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
 	if err != nil {
-		fmt.Printf("  Restart Error: %v\n", err)
-		return
-	}
-	
-	// 成功時は状態に応じたメッセージ表示
-	if status == "stopped" {
-		fmt.Printf("  Restart: VM was stopped, started successfully\n")
-	} else {
-		fmt.Printf("  Restart: Requested successfully\n")
+		log.Fatalf("リーダー選出ロック作成エラー: %v", err)
 	}
+
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: *leaseDuration,
+		RenewDeadline: *renewDeadline,
+		RetryPeriod:   *retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: runWorkers,
+			OnStoppedLeading: func() {
+				log.Printf("リーダーシップを喪失しました: %s", identity)
+			},
+			OnNewLeader: func(newLeader string) {
+				if newLeader != identity {
+					log.Printf("新しいリーダー: %s", newLeader)
+				}
+			},
+		},
+	})
 }
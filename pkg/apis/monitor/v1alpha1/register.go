@@ -0,0 +1,35 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName はこのAPIグループの名前です
+const GroupName = "monitor.xpadev.net"
+
+// SchemeGroupVersion はこのAPIグループ/バージョンを識別するGroupVersionです
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// Resource はリソース名からGroupResourceを組み立てます
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+var (
+	// SchemeBuilder はこのパッケージの型をSchemeに登録するためのビルダーです
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme はこのパッケージの型をSchemeに追加します
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// addKnownTypes はNodeRestartPolicy/NodeRestartPolicyListをSchemeに登録します
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&NodeRestartPolicy{},
+		&NodeRestartPolicyList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,102 @@
+// Package v1alpha1 はmonitor.xpadev.net/v1alpha1グループのAPI型を定義します
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeRestartPolicy はKubernetesノードとProxmoxのVM、及び再起動の挙動を結びつけるクラスタスコープのリソースです
+// 以前pkg/configの設定ファイルにあった静的YAMLのノードマッピングを置き換えます
+type NodeRestartPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeRestartPolicySpec   `json:"spec"`
+	Status NodeRestartPolicyStatus `json:"status,omitempty"`
+}
+
+// NodeRestartPolicySpec はNodeRestartPolicyの望ましい状態です
+type NodeRestartPolicySpec struct {
+	// NodeSelector は対象のKubernetesノードを選択します
+	NodeSelector NodeSelector `json:"nodeSelector"`
+
+	// ProxmoxNode は対象ノードが動作しているProxmoxホスト名です
+	ProxmoxNode string `json:"proxmoxNode"`
+
+	// VMID は対象ノードに対応するProxmoxのVM IDです
+	VMID int32 `json:"vmid"`
+
+	// NotReadyThreshold はこの時間以上NotReadyが続いたら再起動対象とみなす閾値です
+	// 省略時は1分を既定値とします
+	NotReadyThreshold metav1.Duration `json:"notReadyThreshold,omitempty"`
+
+	// RestartCooldown は直近の再起動からこの時間が経過するまで再度の再起動を行いません
+	RestartCooldown metav1.Duration `json:"restartCooldown,omitempty"`
+
+	// MaxRestartsPerHour は直近1時間に許容する再起動回数の上限です。超過した場合は再起動を行わず通知のみ行います
+	MaxRestartsPerHour int32 `json:"maxRestartsPerHour,omitempty"`
+
+	// Notify は通知のルーティング設定です
+	Notify NotifyRouting `json:"notify,omitempty"`
+}
+
+// NodeSelector はKubernetesノードをノード名またはラベルで選択します
+type NodeSelector struct {
+	// NodeName が設定されている場合は完全一致でノードを選択します
+	NodeName string `json:"nodeName,omitempty"`
+
+	// MatchLabels が設定されている場合はラベルの一致でノードを選択します
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// NotifyRouting は通知先シンク名(pkg/notify.Notifier実装の名前)のルーティング設定です
+type NotifyRouting struct {
+	// Sinks は通知を送るシンク名の一覧です。
+	// コントローラはこの値を参照せず、値に関わらず設定済みの全シンクへ通知します
+	Sinks []string `json:"sinks,omitempty"`
+}
+
+// NodeRestartPolicyStatus はコントローラが観測/記録した実績です
+type NodeRestartPolicyStatus struct {
+	// RestartHistory は直近の再起動実行履歴です
+	RestartHistory []RestartRecord `json:"restartHistory,omitempty"`
+
+	// Conditions はこのポリシーの現在の状態です
+	Conditions []NodeRestartPolicyCondition `json:"conditions,omitempty"`
+}
+
+// RestartRecord は1回の再起動実行の記録です
+type RestartRecord struct {
+	Time      metav1.Time `json:"time"`
+	Reason    string      `json:"reason"`
+	Succeeded bool        `json:"succeeded"`
+	Message   string      `json:"message,omitempty"`
+}
+
+// NodeRestartPolicyCondition はNodeRestartPolicyの状態を表すConditionです
+type NodeRestartPolicyCondition struct {
+	Type               string                 `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// 既知のNodeRestartPolicyCondition.Type
+const (
+	ConditionTypeCooldownActive    = "CooldownActive"
+	ConditionTypeRestartRateLimited = "RestartRateLimited"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeRestartPolicyList はNodeRestartPolicyのリストです
+type NodeRestartPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeRestartPolicy `json:"items"`
+}
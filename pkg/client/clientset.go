@@ -0,0 +1,157 @@
+// Package client は monitor.xpadev.net/v1alpha1 のための手書きの薄い型付きクライアントです
+// 本来はk8s.io/code-generatorで生成すべきものですが、このリポジトリはgo.mod/ビルドツールチェインを
+// 同梱していないため、client-goのRESTClientを直接ラップする最小限の実装を手で書いています
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	monitorv1alpha1 "github.com/xpadev/k8s-node-monitor/pkg/apis/monitor/v1alpha1"
+)
+
+var scheme = runtime.NewScheme()
+var parameterCodec = runtime.NewParameterCodec(scheme)
+
+func init() {
+	if err := monitorv1alpha1.AddToScheme(scheme); err != nil {
+		panic(fmt.Sprintf("monitor v1alpha1 scheme登録エラー: %v", err))
+	}
+}
+
+// Clientset は monitor.xpadev.net/v1alpha1 APIグループへのアクセスを提供します
+type Clientset struct {
+	restClient rest.Interface
+}
+
+// NewForConfig はkubeconfigのrest.Configからmonitor.xpadev.net用のClientsetを作成します
+func NewForConfig(c *rest.Config) (*Clientset, error) {
+	config := *c
+	config.ContentConfig.GroupVersion = &monitorv1alpha1.SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = serializer.NewCodecFactory(scheme).WithoutConversion()
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+
+	restClient, err := rest.RESTClientFor(&config)
+	if err != nil {
+		return nil, err
+	}
+	return &Clientset{restClient: restClient}, nil
+}
+
+// NodeRestartPolicies はクラスタスコープのNodeRestartPolicyインターフェースを返します
+func (c *Clientset) NodeRestartPolicies() NodeRestartPolicyInterface {
+	return &nodeRestartPolicies{client: c.restClient}
+}
+
+// NodeRestartPolicyInterface はNodeRestartPolicyへのCRUD操作です
+type NodeRestartPolicyInterface interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*monitorv1alpha1.NodeRestartPolicy, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*monitorv1alpha1.NodeRestartPolicyList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Create(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.CreateOptions) (*monitorv1alpha1.NodeRestartPolicy, error)
+	Update(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.UpdateOptions) (*monitorv1alpha1.NodeRestartPolicy, error)
+	UpdateStatus(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.UpdateOptions) (*monitorv1alpha1.NodeRestartPolicy, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+}
+
+const resourcePlural = "noderestartpolicies"
+
+type nodeRestartPolicies struct {
+	client rest.Interface
+}
+
+func (c *nodeRestartPolicies) Get(ctx context.Context, name string, opts metav1.GetOptions) (*monitorv1alpha1.NodeRestartPolicy, error) {
+	result := &monitorv1alpha1.NodeRestartPolicy{}
+	err := c.client.Get().
+		Resource(resourcePlural).
+		Name(name).
+		VersionedParams(&opts, parameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *nodeRestartPolicies) List(ctx context.Context, opts metav1.ListOptions) (*monitorv1alpha1.NodeRestartPolicyList, error) {
+	result := &monitorv1alpha1.NodeRestartPolicyList{}
+	err := c.client.Get().
+		Resource(resourcePlural).
+		VersionedParams(&opts, parameterCodec).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *nodeRestartPolicies) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.client.Get().
+		Resource(resourcePlural).
+		VersionedParams(&opts, parameterCodec).
+		Timeout(timeoutOrDefault(opts.TimeoutSeconds)).
+		Watch(ctx)
+}
+
+func (c *nodeRestartPolicies) Create(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.CreateOptions) (*monitorv1alpha1.NodeRestartPolicy, error) {
+	result := &monitorv1alpha1.NodeRestartPolicy{}
+	err := c.client.Post().
+		Resource(resourcePlural).
+		VersionedParams(&opts, parameterCodec).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *nodeRestartPolicies) Update(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.UpdateOptions) (*monitorv1alpha1.NodeRestartPolicy, error) {
+	result := &monitorv1alpha1.NodeRestartPolicy{}
+	err := c.client.Put().
+		Resource(resourcePlural).
+		Name(policy.Name).
+		VersionedParams(&opts, parameterCodec).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+// UpdateStatus は .status サブリソースのみを更新します
+func (c *nodeRestartPolicies) UpdateStatus(ctx context.Context, policy *monitorv1alpha1.NodeRestartPolicy, opts metav1.UpdateOptions) (*monitorv1alpha1.NodeRestartPolicy, error) {
+	result := &monitorv1alpha1.NodeRestartPolicy{}
+	err := c.client.Put().
+		Resource(resourcePlural).
+		Name(policy.Name).
+		SubResource("status").
+		VersionedParams(&opts, parameterCodec).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+	return result, err
+}
+
+func (c *nodeRestartPolicies) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource(resourcePlural).
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+func timeoutOrDefault(seconds *int64) time.Duration {
+	if seconds == nil {
+		return 30 * time.Second
+	}
+	return time.Duration(*seconds) * time.Second
+}
+
+var _ schema.GroupVersionResource = monitorv1alpha1.SchemeGroupVersion.WithResource(resourcePlural)
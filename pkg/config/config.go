@@ -8,10 +8,11 @@ import (
 )
 
 // Config は全体の設定構造体
+// ノードとProxmox VMの対応関係はmonitor.xpadev.net/v1alpha1 NodeRestartPolicy CRDに移行したため、
+// ここにはクラスタ接続先に依存しない設定のみを残している
 type Config struct {
-	Proxmox ProxmoxConfig `yaml:"proxmox"`
-	Discord DiscordConfig `yaml:"discord"`
-	Nodes   []NodeMapping `yaml:"nodes"`
+	Proxmox ProxmoxConfig      `yaml:"proxmox"`
+	Sinks   []NotifySinkConfig `yaml:"sinks"`
 }
 
 // ProxmoxConfig はProxmox APIの設定
@@ -23,17 +24,23 @@ type ProxmoxConfig struct {
 	TokenSecret string `yaml:"tokenSecret"` // 認証にTokenを使用する場合
 }
 
-// DiscordConfig はDiscord Webhookの設定
-type DiscordConfig struct {
-	WebhookURL string `yaml:"webhookUrl"`
-	Enabled    bool   `yaml:"enabled"`
-}
-
-// NodeMapping はKubernetesノード名とProxmoxの対応関係
-type NodeMapping struct {
-	KubernetesNodeName string `yaml:"kubernetesNodeName"`
-	ProxmoxNode        string `yaml:"proxmoxNode"`
-	VMID               int    `yaml:"vmid"`
+// NotifySinkConfig は通知先(pkg/notify.Notifier実装)1つ分の設定です
+// Typeで利用するバックエンドを選び、Eventsで通知対象のイベント種別を絞り込みます
+// (例: チャットには全イベント、PagerDutyにはrestart-failedのみ)
+type NotifySinkConfig struct {
+	// Type は "discord", "slack", "teams", "pagerduty", "webhook" のいずれかです
+	Type string `yaml:"type"`
+	// Name は複数の同種シンクを区別するための任意の識別子です。省略時はTypeを使います
+	Name string `yaml:"name,omitempty"`
+	// Enabled はこのシンクを有効にするかどうかです
+	Enabled bool `yaml:"enabled"`
+	// WebhookURL はdiscord/slack/teams/webhookで使うWebhook URLです
+	WebhookURL string `yaml:"webhookUrl,omitempty"`
+	// RoutingKey はpagerdutyで使うEvents API v2のIntegration Keyです
+	RoutingKey string `yaml:"routingKey,omitempty"`
+	// Events はこのシンクへ通知するイベント種別です。空の場合は全イベントを通知します
+	// 値は pkg/notify.EventKind の文字列表現 (not-ready, restart-triggered, restart-failed, recovered)
+	Events []string `yaml:"events,omitempty"`
 }
 
 // LoadConfig は指定されたパスから設定ファイルを読み込みます
@@ -56,13 +63,3 @@ func LoadConfig(path string) (*Config, error) {
 
 	return &config, nil
 }
-
-// FindNodeMapping はKubernetesのノード名からProxmoxのマッピング情報を探します
-func (c *Config) FindNodeMapping(nodeName string) *NodeMapping {
-	for _, node := range c.Nodes {
-		if node.KubernetesNodeName == nodeName {
-			return &node
-		}
-	}
-	return nil
-}
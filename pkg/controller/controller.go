@@ -0,0 +1,175 @@
+// Package controller はNodeのSharedInformerを監視し、NotReadyノードへの
+// 通知/再起動処理をworkqueue経由で行うコントローラを提供します
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	monitorclient "github.com/xpadev/k8s-node-monitor/pkg/client"
+	"github.com/xpadev/k8s-node-monitor/pkg/k8s"
+	"github.com/xpadev/k8s-node-monitor/pkg/notify"
+	"github.com/xpadev/k8s-node-monitor/pkg/proxmox"
+)
+
+// resyncPeriod はSharedInformerFactoryの定期フルリサーブ間隔です
+const resyncPeriod = 30 * time.Second
+
+// Controller はNodeの変化をworkqueueにキューイングし、
+// ワーカーがデキューしてNotify/Restart処理を行うコントローラです
+type Controller struct {
+	informerFactory informers.SharedInformerFactory
+	nodeInformer    cache.SharedIndexInformer
+	nodeLister      corelisters.NodeLister
+	policyInformer  cache.SharedIndexInformer
+	policyClient    *monitorclient.Clientset
+	workqueue       workqueue.RateLimitingInterface
+
+	proxmoxClient          *proxmox.Client
+	notifier               notify.Notifier
+	enableRestart          bool
+	restartRecoveryTimeout time.Duration
+	restartLimiter         *restartLimiter
+
+	// lastNodeStatus はノード名ごとに前回syncNodeで観測したStatusを保持します
+	// 更新イベント/30秒ごとのresyncで同じ状態を何度観測してもカウンタが増え続けないよう、
+	// Ready<->NotReadyの遷移を検出するために使います
+	lastNodeStatus sync.Map
+}
+
+// New は新しいControllerを作成します
+func New(clientset kubernetes.Interface, policyClient *monitorclient.Clientset, proxmoxClient *proxmox.Client, notifier notify.Notifier, enableRestart bool, restartRecoveryTimeout time.Duration) *Controller {
+	informerFactory := informers.NewSharedInformerFactory(clientset, resyncPeriod)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+
+	c := &Controller{
+		informerFactory:        informerFactory,
+		nodeInformer:           nodeInformer.Informer(),
+		nodeLister:             nodeInformer.Lister(),
+		policyInformer:         newPolicyInformer(policyClient),
+		policyClient:           policyClient,
+		workqueue:              workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		proxmoxClient:          proxmoxClient,
+		notifier:               notifier,
+		enableRestart:          enableRestart,
+		restartRecoveryTimeout: restartRecoveryTimeout,
+		restartLimiter:         newRestartLimiter(),
+	}
+
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueueNode,
+		UpdateFunc: func(old, new interface{}) { c.enqueueNode(new) },
+		DeleteFunc: c.enqueueNode,
+	})
+
+	return c
+}
+
+// enqueueNode はNodeオブジェクトのキーをworkqueueに積みます
+func (c *Controller) enqueueNode(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("キー取得エラー: %w", err))
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// StartInformers はNode/NodeRestartPolicyのinformerを起動し、キャッシュが同期されるまで
+// ブロックします。リーダー選出の有無に関わらず全レプリカで起動し、/readyzがどのレプリカでも
+// 正しく準備完了を報告できるようにします(フォロワーをコールドスタンバイにしないため)。
+// stopChがcloseされるとinformerも停止します
+func (c *Controller) StartInformers(stopCh <-chan struct{}) error {
+	c.informerFactory.Start(stopCh)
+	go c.policyInformer.Run(stopCh)
+
+	if ok := cache.WaitForCacheSync(stopCh, c.nodeInformer.HasSynced, c.policyInformer.HasSynced); !ok {
+		return fmt.Errorf("informerのキャッシュ同期に失敗しました")
+	}
+	return nil
+}
+
+// RunWorkers はworkers個のワーカーを起動し、stopChがcloseされるまでworkqueueを処理します
+// 再起動のようなsideffectを伴う処理はここからしか行われないため、リーダー選出を使う場合は
+// リーダーである間だけ呼び出すようにします(StartInformersとは別にゲートできます)
+func (c *Controller) RunWorkers(workers int, stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// HasSynced はNode/NodeRestartPolicy両方のinformerキャッシュが同期済みかどうかを返します
+// /readyzのreadiness判定に使われます
+func (c *Controller) HasSynced() bool {
+	return c.nodeInformer.HasSynced() && c.policyInformer.HasSynced()
+}
+
+// runWorker はworkqueueからキーを取り出せなくなるまでprocessNextItemを繰り返します
+// wait.Until自体はpanicを回収しないため、1ノードの処理で起きたpanicが
+// プロセス全体を落とさないようここでHandleCrashします
+func (c *Controller) runWorker() {
+	defer runtime.HandleCrash()
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem はworkqueueから1件キーを取り出し、syncHandlerに渡します
+func (c *Controller) processNextItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.workqueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.workqueue.Forget(obj)
+		runtime.HandleError(fmt.Errorf("workqueueに文字列以外のキーが入っています: %#v", obj))
+		return true
+	}
+
+	if err := c.syncHandler(key); err != nil {
+		c.workqueue.AddRateLimited(key)
+		runtime.HandleError(fmt.Errorf("'%s'の同期に失敗しました、再キューします: %w", key, err))
+		return true
+	}
+
+	c.workqueue.Forget(key)
+	return true
+}
+
+// syncHandler はキーからリスター経由でNodeを取得し、現在の状態に応じて
+// 通知/再起動を行います。リスターにノードが見つからない場合は削除されたものとして無視します
+func (c *Controller) syncHandler(key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("無効なリソースキー: %s", key)
+	}
+
+	node, err := c.nodeLister.Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// ノードが削除済み。これ以上何もすることはない
+			return nil
+		}
+		return err
+	}
+
+	c.syncNode(k8s.NewNodeInfo(node))
+	return nil
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	monitorv1alpha1 "github.com/xpadev/k8s-node-monitor/pkg/apis/monitor/v1alpha1"
+	monitorclient "github.com/xpadev/k8s-node-monitor/pkg/client"
+)
+
+// newPolicyInformer はNodeRestartPolicyをwatchするSharedIndexInformerを作成します
+// NodeRestartPolicyはクラスタスコープのリソースなのでNamespaceは常に空です
+func newPolicyInformer(policyClient *monitorclient.Clientset) cache.SharedIndexInformer {
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return policyClient.NodeRestartPolicies().List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return policyClient.NodeRestartPolicies().Watch(context.TODO(), options)
+		},
+	}
+
+	return cache.NewSharedIndexInformer(
+		listWatch,
+		&monitorv1alpha1.NodeRestartPolicy{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// findPolicyForNode はノード名に一致するNodeRestartPolicyをインデクサから探します
+// NodeSelector.NodeNameによる完全一致、またはMatchLabelsによるラベル一致のいずれかで選択します
+func (c *Controller) findPolicyForNode(nodeName string) (*monitorv1alpha1.NodeRestartPolicy, error) {
+	node, err := c.nodeLister.Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, obj := range c.policyInformer.GetIndexer().List() {
+		policy, ok := obj.(*monitorv1alpha1.NodeRestartPolicy)
+		if !ok {
+			continue
+		}
+
+		if policy.Spec.NodeSelector.NodeName == nodeName {
+			return policy, nil
+		}
+
+		if len(policy.Spec.NodeSelector.MatchLabels) > 0 && labelsMatch(node.Labels, policy.Spec.NodeSelector.MatchLabels) {
+			return policy, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// labelsMatch はwantがallの部分集合かどうかを確認します
+func labelsMatch(all, want map[string]string) bool {
+	for k, v := range want {
+		if all[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// recordRestartHistory は再起動実行結果を.statusのRestartHistoryに追記します
+func (c *Controller) recordRestartHistory(policy *monitorv1alpha1.NodeRestartPolicy, reason string, succeeded bool, message string) {
+	updated := policy.DeepCopy()
+	updated.Status.RestartHistory = append(updated.Status.RestartHistory, monitorv1alpha1.RestartRecord{
+		Time:      metav1.NewTime(time.Now()),
+		Reason:    reason,
+		Succeeded: succeeded,
+		Message:   message,
+	})
+
+	if _, err := c.policyClient.NodeRestartPolicies().UpdateStatus(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+		fmt.Printf("  Status更新エラー: %v\n", err)
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// restartLimiter はノードごとの再起動クールダウンと1時間あたりの再起動回数上限を
+// インメモリで管理します。壊れたVMを無限にリセットし続けることを防ぐためのものです
+type restartLimiter struct {
+	mu        sync.Mutex
+	lastAt    map[string]time.Time
+	restartAt map[string][]time.Time
+}
+
+// newRestartLimiter は空のrestartLimiterを作成します
+func newRestartLimiter() *restartLimiter {
+	return &restartLimiter{
+		lastAt:    make(map[string]time.Time),
+		restartAt: make(map[string][]time.Time),
+	}
+}
+
+// allow はnodeNameに対する再起動がcooldown/maxPerHourの制約に照らして許可されるかを返します
+// 許可されない場合、その理由を人間向けの文字列で返します
+func (l *restartLimiter) allow(nodeName string, cooldown time.Duration, maxPerHour int32, now time.Time) (bool, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if cooldown > 0 {
+		if last, ok := l.lastAt[nodeName]; ok {
+			if remaining := cooldown - now.Sub(last); remaining > 0 {
+				return false, "クールダウン期間中です(残り " + remaining.Round(time.Second).String() + ")"
+			}
+		}
+	}
+
+	if maxPerHour > 0 {
+		recent := pruneOlderThan(l.restartAt[nodeName], now.Add(-time.Hour))
+		l.restartAt[nodeName] = recent
+		if int32(len(recent)) >= maxPerHour {
+			return false, fmt.Sprintf("直近1時間の再起動回数が上限(%d回)に達しています", maxPerHour)
+		}
+	}
+
+	return true, ""
+}
+
+// record はnodeNameに対して再起動を実行したことを記録します
+func (l *restartLimiter) record(nodeName string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lastAt[nodeName] = now
+	l.restartAt[nodeName] = append(pruneOlderThan(l.restartAt[nodeName], now.Add(-time.Hour)), now)
+}
+
+// pruneOlderThan はcutoffより古いエントリを取り除いた新しいスライスを返します
+func pruneOlderThan(times []time.Time, cutoff time.Time) []time.Time {
+	kept := times[:0:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	monitorv1alpha1 "github.com/xpadev/k8s-node-monitor/pkg/apis/monitor/v1alpha1"
+	"github.com/xpadev/k8s-node-monitor/pkg/k8s"
+	"github.com/xpadev/k8s-node-monitor/pkg/metrics"
+	"github.com/xpadev/k8s-node-monitor/pkg/notify"
+)
+
+// defaultNotReadyRestartThreshold はNodeRestartPolicy.Spec.NotReadyThresholdが
+// 未指定の場合に使われる既定の閾値です
+const defaultNotReadyRestartThreshold = 1 * time.Minute
+
+// defaultRestartRecoveryTimeout は再起動後にNodeがReadyに戻るまで待つ既定の時間です
+const defaultRestartRecoveryTimeout = 5 * time.Minute
+
+// recoveryPollInterval はReady復帰待ちの際にnodeListerを参照する間隔です
+const recoveryPollInterval = 5 * time.Second
+
+// syncNode は1ノード分の状態を見て、必要に応じて通知/再起動処理を行います
+// main.goにあったdisplayNodeInfoの判断ロジックをワーカー側に移し、状態遷移の検出と
+// NodeRestartPolicyによる閾値の指定を加えたものです
+func (c *Controller) syncNode(node k8s.NodeInfo) {
+	policy, err := c.findPolicyForNode(node.Name)
+	if err != nil {
+		fmt.Printf("  NodeRestartPolicy検索エラー: %v\n", err)
+	}
+
+	// 更新イベントやresyncのたびに同じ状態を何度も観測するため、前回観測したStatusと
+	// 比較して遷移があった場合にのみNotReady回数のメトリクス、recovered通知、および冗長なダンプ出力を発火させる。
+	// NotReady中のnot-ready通知や再起動判定は同期のたびに行う
+	prevStatusVal, _ := c.lastNodeStatus.Load(node.Name)
+	prevStatus, _ := prevStatusVal.(string)
+	changed := prevStatus != node.Status
+	c.lastNodeStatus.Store(node.Name, node.Status)
+
+	if changed {
+		fmt.Printf("Name: %s\n", node.Name)
+	}
+
+	if node.Status == "Ready" {
+		if changed {
+			fmt.Printf("  Status: %s\n", node.Status)
+		}
+		metrics.NodeNotReadySeconds.WithLabelValues(node.Name).Set(0)
+
+		if prevStatus == "NotReady" {
+			// NotReady->Readyへの遷移。PagerDutyなどdedup_keyベースのシンクが
+			// 開いたインシデントをresolveできるよう、復旧を通知する
+			c.notify(node, policy, notify.EventRecovered)
+		}
+	} else {
+		if changed {
+			fmt.Printf("  Status: %s (for %s)\n", node.Status, node.NotReadyDuration)
+		}
+
+		if prevStatus != "NotReady" {
+			metrics.NodeNotReadyTotal.WithLabelValues(node.Name).Inc()
+		}
+		metrics.NodeNotReadySeconds.WithLabelValues(node.Name).Set(time.Since(node.LastTransition).Seconds())
+
+		threshold := defaultNotReadyRestartThreshold
+		if policy != nil && policy.Spec.NotReadyThreshold.Duration > 0 {
+			threshold = policy.Spec.NotReadyThreshold.Duration
+		}
+
+		if !c.enableRestart {
+			fmt.Printf("  Action: Automatic restart disabled\n")
+			c.notify(node, policy, notify.EventNotReady)
+		} else if time.Since(node.LastTransition) <= threshold {
+			fmt.Printf("  Action: Node is NotReady but for less than %s, no restart needed\n", threshold)
+			c.notify(node, policy, notify.EventNotReady)
+		} else {
+			c.handleNodeRestart(node, policy)
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	// 共通情報の表示。状態に変化があった場合のみ出力し、定常状態のresync/Updateイベントで
+	// 毎回同じダンプがログを埋め尽くさないようにする
+	fmt.Printf("  IP: %s\n", node.IP)
+	fmt.Printf("  Kubelet Version: %s\n", node.KubeletVersion)
+	fmt.Printf("  OS/Arch: %s/%s\n", node.OSImage, node.Architecture)
+	fmt.Printf("  Allocatable Resources:\n")
+	fmt.Printf("    CPU: %s\n", node.AllocatableCPU)
+	fmt.Printf("    Memory: %s\n", node.AllocatableMemory)
+	fmt.Printf("    Pods: %s\n", node.AllocatablePods)
+	fmt.Println()
+}
+
+// notify はpkg/notify.Notifierへノード状態を通知します
+func (c *Controller) notify(node k8s.NodeInfo, policy *monitorv1alpha1.NodeRestartPolicy, kind notify.EventKind) {
+	if c.notifier == nil {
+		return
+	}
+
+	vmInfo := ""
+	if policy != nil {
+		vmInfo = fmt.Sprintf("Proxmox Node: %s, VM ID: %d", policy.Spec.ProxmoxNode, policy.Spec.VMID)
+	}
+
+	event := notify.NodeEvent{
+		Kind:     kind,
+		NodeName: node.Name,
+		Status:   node.Status,
+		Duration: node.NotReadyDuration,
+		IP:       node.IP,
+		VMInfo:   vmInfo,
+	}
+
+	if err := c.notifier.Notify(context.Background(), event); err != nil {
+		fmt.Printf("  通知エラー: %v\n", err)
+	} else {
+		fmt.Printf("  通知: 送信成功\n")
+	}
+}
+
+// handleNodeRestart はNotReadyノードの再起動処理を行います
+func (c *Controller) handleNodeRestart(node k8s.NodeInfo, policy *monitorv1alpha1.NodeRestartPolicy) {
+	if policy == nil {
+		fmt.Printf("  Action: No NodeRestartPolicy found for node '%s'\n", node.Name)
+		c.notify(node, nil, notify.EventNotReady)
+		return
+	}
+
+	if allowed, reason := c.restartLimiter.allow(node.Name, policy.Spec.RestartCooldown.Duration, policy.Spec.MaxRestartsPerHour, time.Now()); !allowed {
+		fmt.Printf("  Action: 再起動をスキップしました(%s): %s\n", node.Name, reason)
+		c.notify(node, policy, notify.EventNotReady)
+		return
+	}
+
+	status, err := c.proxmoxClient.GetVMStatus(policy.Spec.ProxmoxNode, int(policy.Spec.VMID))
+	if err != nil {
+		fmt.Printf("  Status Error: VM状態取得失敗: %v\n", err)
+		c.notify(node, policy, notify.EventNotReady)
+		return
+	}
+
+	fmt.Printf("  Current VM Status: %s\n", status)
+	fmt.Printf("  Action: Restarting node via Proxmox (Node: %s, VMID: %d)\n",
+		policy.Spec.ProxmoxNode, policy.Spec.VMID)
+
+	c.notify(node, policy, notify.EventRestartTriggered)
+	c.restartLimiter.record(node.Name, time.Now())
+
+	if err := c.proxmoxClient.RestartVM(policy.Spec.ProxmoxNode, int(policy.Spec.VMID)); err != nil {
+		fmt.Printf("  Restart Error: %v\n", err)
+		metrics.RestartAttemptsTotal.WithLabelValues(node.Name, "failure").Inc()
+		c.notify(node, policy, notify.EventRestartFailed)
+		c.recordRestartHistory(policy, "NotReady", false, err.Error())
+		return
+	}
+
+	if status == "stopped" {
+		fmt.Printf("  Restart: VM was stopped, started successfully\n")
+	} else {
+		fmt.Printf("  Restart: Requested successfully\n")
+	}
+	metrics.RestartAttemptsTotal.WithLabelValues(node.Name, "success").Inc()
+	c.recordRestartHistory(policy, "NotReady", true, "")
+
+	// 復帰待ち(最大--restart-recovery-timeout)はworkqueueのハンドラをブロックさせず、
+	// 別goroutineで行う。ここで同期的に待つとworkerCount分の再起動が重なっただけで
+	// 他の全ノードの同期が数分単位で詰まってしまうため
+	go c.waitForRecovery(node, policy)
+}
+
+// waitForRecovery は再起動後にNodeがReadyへ復帰するのを待ち、タイムアウトした場合のみ
+// "restart-failed"として通知します。復帰できた場合の"recovered"通知はsyncNodeの
+// NotReady->Ready遷移検出側に任せるため、ここからは送りません(二重通知防止)
+// handleNodeRestartからgoroutineとして起動されるため、workqueueのワーカーは
+// 復帰を待たずに次のアイテムの処理へ進めます
+func (c *Controller) waitForRecovery(node k8s.NodeInfo, policy *monitorv1alpha1.NodeRestartPolicy) {
+	defer runtime.HandleCrash()
+
+	timeout := c.restartRecoveryTimeout
+	if timeout <= 0 {
+		timeout = defaultRestartRecoveryTimeout
+	}
+
+	fmt.Printf("  Action: '%s'がReadyに復帰するのを最大%sまで待機します\n", node.Name, timeout)
+
+	err := wait.PollImmediate(recoveryPollInterval, timeout, func() (bool, error) {
+		current, err := c.nodeLister.Get(node.Name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return k8s.NewNodeInfo(current).Status == "Ready", nil
+	})
+
+	if err != nil {
+		fmt.Printf("  Recovery Error: '%s'はタイムアウトまでにReadyへ復帰しませんでした: %v\n", node.Name, err)
+		c.notify(node, policy, notify.EventRestartFailed)
+		c.recordRestartHistory(policy, "RecoveryTimeout", false, err.Error())
+		return
+	}
+
+	// "recovered"の通知はsyncNodeのNotReady->Ready遷移検出側で行う(二重通知を避けるため)。
+	// informerがReadyへのUpdateイベントを受け取れば自然にそちらが発火する
+	fmt.Printf("  Recovery: '%s'はReadyに復帰しました\n", node.Name)
+}
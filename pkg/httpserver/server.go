@@ -0,0 +1,64 @@
+// Package httpserver はコントローラの運用用HTTPエンドポイント
+// (/healthz, /readyz, /metrics, /debug/pprof/*)をまとめて提供します
+package httpserver
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server は運用用HTTPエンドポイントを保持します
+type Server struct {
+	addr            string
+	enableProfiling bool
+	readyFunc       func() bool
+}
+
+// New は新しいServerを作成します
+// readyFuncはinformerのキャッシュ同期が完了しているかどうかを返す関数で、/readyzから呼ばれます
+func New(addr string, enableProfiling bool, readyFunc func() bool) *Server {
+	return &Server{
+		addr:            addr,
+		enableProfiling: enableProfiling,
+		readyFunc:       readyFunc,
+	}
+}
+
+// Handler はこのServerが提供するエンドポイントを持つhttp.Handlerを返します
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !s.readyFunc() {
+			http.Error(w, "caches not synced", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}))
+
+	if s.enableProfiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	return mux
+}
+
+// ListenAndServe はHTTPサーバーをブロッキングで起動します
+func (s *Server) ListenAndServe() error {
+	return http.ListenAndServe(s.addr, s.Handler())
+}
@@ -11,7 +11,8 @@ import (
 
 // Client represents a Kubernetes client
 type Client struct {
-	clientset *kubernetes.Clientset
+	clientset  *kubernetes.Clientset
+	restConfig *rest.Config
 }
 
 // NewClient creates a new Kubernetes client
@@ -33,5 +34,18 @@ func NewClient() (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{clientset: clientset}, nil
+	return &Client{clientset: clientset, restConfig: config}, nil
+}
+
+// Clientset は内部で保持しているkubernetes.Clientsetを返します
+// informer等、Client単体では表現しきれない機能を組み立てる呼び出し元向けです
+func (c *Client) Clientset() *kubernetes.Clientset {
+	return c.clientset
+}
+
+// RESTConfig は接続に使用しているrest.Configを返します
+// monitor.xpadev.net/v1alpha1用の型付きクライアント等、他のクライアントを同じクラスタ向けに
+// 組み立てたい呼び出し元向けです
+func (c *Client) RESTConfig() *rest.Config {
+	return c.restConfig
 }
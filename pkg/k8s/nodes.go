@@ -44,6 +44,12 @@ func (c *Client) GetNodes() ([]NodeInfo, error) {
 	return nodes, nil
 }
 
+// NewNodeInfo は*corev1.Nodeから NodeInfo を構築します
+// informerのリスターから取得したノードをそのままイベントハンドラ/ワーカーで扱えるようにするための公開エントリーポイントです
+func NewNodeInfo(node *corev1.Node) NodeInfo {
+	return processNodeInfo(*node, time.Now())
+}
+
 // processNodeInfo は単一ノードの情報を処理します
 func processNodeInfo(node corev1.Node, now time.Time) NodeInfo {
 	// ノードステータスとその経過時間の処理
@@ -0,0 +1,64 @@
+// Package metrics はこのコントローラが公開するPrometheusメトリクスを定義します
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// namespace は全メトリクス共通のPrometheus namespaceです
+const namespace = "nodemonitor"
+
+var (
+	// NodeNotReadyTotal はノードがReady->NotReadyに遷移した回数です。同じNotReady状態を
+	// Update/resyncで何度観測しても増えません(1エピソードにつき1回)
+	NodeNotReadyTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "node_notready_total",
+		Help:      "Number of times a node has transitioned from Ready to NotReady",
+	}, []string{"node"})
+
+	// RestartAttemptsTotal はProxmox VM再起動の試行回数です。resultは"success"または"failure"です
+	RestartAttemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "restart_attempts_total",
+		Help:      "Number of Proxmox VM restart attempts",
+	}, []string{"node", "result"})
+
+	// ProxmoxAPIRequestsTotal はProxmox APIへのリクエスト回数です
+	ProxmoxAPIRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "proxmox_api_requests_total",
+		Help:      "Number of requests made to the Proxmox API",
+	}, []string{"action", "code"})
+
+	// ProxmoxAPIRequestDurationSeconds はProxmox APIリクエストのレイテンシです
+	ProxmoxAPIRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "proxmox_api_request_duration_seconds",
+		Help:      "Latency of requests made to the Proxmox API",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action"})
+
+	// NodeNotReadySeconds はノードがNotReadyになってから経過した秒数です。Readyに戻ると0になります
+	NodeNotReadySeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_notready_seconds",
+		Help:      "Seconds since the node transitioned to NotReady, 0 when Ready",
+	}, []string{"node"})
+)
+
+// MustRegister はこのパッケージの全メトリクスをregistererに登録します
+func MustRegister(registerer prometheus.Registerer) {
+	registerer.MustRegister(
+		NodeNotReadyTotal,
+		RestartAttemptsTotal,
+		ProxmoxAPIRequestsTotal,
+		ProxmoxAPIRequestDurationSeconds,
+		NodeNotReadySeconds,
+	)
+}
+
+// MustRegisterDefault はこのパッケージの全メトリクスをprometheus.DefaultRegistererに登録します
+// httpserverが使うprometheus.DefaultGathererと対になる、呼び出し元がprometheusパッケージに
+// 直接依存しなくて済むようにするためのヘルパーです
+func MustRegisterDefault() {
+	MustRegister(prometheus.DefaultRegisterer)
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/xpadev/k8s-node-monitor/pkg/config"
+	"github.com/xpadev/k8s-node-monitor/pkg/discord"
+)
+
+// BuildSinks はconfig.NotifySinkConfigの一覧からSinkの一覧を組み立てます
+// 未知のTypeを含む設定が1つでもあれば、スキップせずにエラーを返します
+func BuildSinks(configs []config.NotifySinkConfig) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(configs))
+
+	for _, sinkCfg := range configs {
+		notifier, err := buildNotifier(sinkCfg)
+		if err != nil {
+			return nil, fmt.Errorf("シンク '%s' の作成エラー: %w", sinkName(sinkCfg), err)
+		}
+
+		sinks = append(sinks, Sink{
+			Notifier: notifier,
+			Kinds:    eventKinds(sinkCfg.Events),
+		})
+	}
+
+	return sinks, nil
+}
+
+func sinkName(c config.NotifySinkConfig) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Type
+}
+
+func buildNotifier(c config.NotifySinkConfig) (Notifier, error) {
+	switch c.Type {
+	case "discord":
+		return NewDiscordNotifier(discord.NewWebhookClient(c.WebhookURL, c.Enabled)), nil
+	case "slack":
+		return NewSlackNotifier(c.WebhookURL, c.Enabled), nil
+	case "teams":
+		return NewTeamsNotifier(c.WebhookURL, c.Enabled), nil
+	case "pagerduty":
+		return NewPagerDutyNotifier(c.RoutingKey, c.Enabled), nil
+	case "webhook":
+		return NewWebhookNotifier(c.WebhookURL, c.Enabled), nil
+	default:
+		return nil, fmt.Errorf("未知のシンク種別: %s", c.Type)
+	}
+}
+
+func eventKinds(names []string) []EventKind {
+	if len(names) == 0 {
+		return nil
+	}
+	kinds := make([]EventKind, len(names))
+	for i, name := range names {
+		kinds[i] = EventKind(name)
+	}
+	return kinds
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/xpadev/k8s-node-monitor/pkg/discord"
+)
+
+// DiscordNotifier はpkg/discord.WebhookClientをNotifierとして扱うアダプタです
+type DiscordNotifier struct {
+	client *discord.WebhookClient
+}
+
+// NewDiscordNotifier は新しいDiscordNotifierを作成します
+func NewDiscordNotifier(client *discord.WebhookClient) *DiscordNotifier {
+	return &DiscordNotifier{client: client}
+}
+
+// Name はNotifierインターフェースの実装です
+func (n *DiscordNotifier) Name() string {
+	return "discord"
+}
+
+// Notify はNotifierインターフェースの実装です
+func (n *DiscordNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	if n.client == nil || !n.client.Enabled {
+		return nil
+	}
+
+	isRestarting := event.Kind == EventRestartTriggered
+	return n.client.SendNodeNotReadyNotification(event.NodeName, event.Status, event.Duration, event.IP, event.VMInfo, isRestarting)
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Sink は1つのNotifierと、それに通知すべきEventKindのフィルタの組です
+// Kindsが空の場合は全てのイベント種別を通知対象とします
+type Sink struct {
+	Notifier Notifier
+	Kinds    []EventKind
+}
+
+// accepts はこのシンクがeventを通知対象とするかどうかを返します
+func (s Sink) accepts(kind EventKind) bool {
+	if len(s.Kinds) == 0 {
+		return true
+	}
+	for _, k := range s.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiNotifier は複数のSinkへ並行に通知を送る合成Notifierです
+// 壊れているシンクが1つあっても他のシンクへの通知をブロックしないよう、エラーは集約して返します
+type MultiNotifier struct {
+	sinks []Sink
+}
+
+// NewMulti は新しいMultiNotifierを作成します
+func NewMulti(sinks ...Sink) *MultiNotifier {
+	return &MultiNotifier{sinks: sinks}
+}
+
+// Name はNotifierインターフェースの実装です
+func (m *MultiNotifier) Name() string {
+	return "multi"
+}
+
+// Notify は対象となる全てのSinkへ並行に通知し、発生したエラーをまとめて返します
+func (m *MultiNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(m.sinks))
+
+	for i, sink := range m.sinks {
+		if !sink.accepts(event.Kind) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, sink Sink) {
+			defer wg.Done()
+			if err := sink.Notifier.Notify(ctx, event); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", sink.Notifier.Name(), err)
+			}
+		}(i, sink)
+	}
+
+	wg.Wait()
+
+	return joinErrors(errs)
+}
+
+// joinErrors は複数のエラーのうちnilでないものだけをまとめて1つのerrorにします
+func joinErrors(errs []error) error {
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d件の通知シンクでエラー: %s", len(messages), strings.Join(messages, "; "))
+}
@@ -0,0 +1,37 @@
+// Package notify はノード状態変化の通知先を抽象化するNotifierインターフェースと
+// 各種バックエンド(Discord/Slack/Teams/PagerDuty/汎用Webhook)を提供します
+package notify
+
+import "context"
+
+// EventKind はNodeEventの種別です。シンクごとの重大度フィルタはこの種別で判定します
+type EventKind string
+
+const (
+	// EventNotReady はノードがNotReadyになったことを表します(再起動は行わない)
+	EventNotReady EventKind = "not-ready"
+	// EventRestartTriggered はNotReadyノードに対して再起動を実行したことを表します
+	EventRestartTriggered EventKind = "restart-triggered"
+	// EventRestartFailed は再起動の実行または復旧確認に失敗したことを表します
+	EventRestartFailed EventKind = "restart-failed"
+	// EventRecovered はノードがReadyに復帰したことを表します
+	EventRecovered EventKind = "recovered"
+)
+
+// NodeEvent は1件のノード状態変化を表します
+type NodeEvent struct {
+	Kind     EventKind
+	NodeName string
+	Status   string
+	Duration string
+	IP       string
+	VMInfo   string
+}
+
+// Notifier は1つの通知先バックエンドです
+type Notifier interface {
+	// Name はログや設定で使う識別子です(例: "discord", "slack")
+	Name() string
+	// Notify はeventを通知します。シンクが無効化されている場合はnilを返します
+	Notify(ctx context.Context, event NodeEvent) error
+}
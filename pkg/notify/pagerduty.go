@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// pagerDutyEventsURL はPagerDuty Events API v2のエンドポイントです
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifier はPagerDuty Events API v2向けのNotifierです
+// DedupKeyにノード名を使うことで、同じノードの繰り返すNotReadyイベントを1つのインシデントに集約し、
+// Readyに戻った際は同じキーでresolveイベントを送ってインシデントを自動解消します
+type PagerDutyNotifier struct {
+	RoutingKey string
+	Enabled    bool
+	httpClient *http.Client
+}
+
+// NewPagerDutyNotifier は新しいPagerDutyNotifierを作成します
+func NewPagerDutyNotifier(routingKey string, enabled bool) *PagerDutyNotifier {
+	return &PagerDutyNotifier{
+		RoutingKey: routingKey,
+		Enabled:    enabled,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name はNotifierインターフェースの実装です
+func (n *PagerDutyNotifier) Name() string {
+	return "pagerduty"
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string                 `json:"routing_key"`
+	EventAction string                 `json:"event_action"`
+	DedupKey    string                 `json:"dedup_key"`
+	Payload     *pagerDutyEventPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyEventPayload struct {
+	Summary  string `json:"summary"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+// Notify はNotifierインターフェースの実装です
+// EventRecoveredはresolveイベントとして、それ以外はtriggerイベントとして送信します
+func (n *PagerDutyNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	if !n.Enabled || n.RoutingKey == "" {
+		return nil
+	}
+
+	pdEvent := pagerDutyEvent{
+		RoutingKey: n.RoutingKey,
+		// dedup_keyにノード名を使い、同じノードの繰り返し通知を1インシデントに集約する
+		DedupKey: event.NodeName,
+	}
+
+	if event.Kind == EventRecovered {
+		pdEvent.EventAction = "resolve"
+	} else {
+		pdEvent.EventAction = "trigger"
+		pdEvent.Payload = &pagerDutyEventPayload{
+			Summary:  fmt.Sprintf("Node %s is %s (for %s)", event.NodeName, event.Status, event.Duration),
+			Source:   event.NodeName,
+			Severity: pagerDutySeverity(event.Kind),
+		}
+	}
+
+	body, err := json.Marshal(pdEvent)
+	if err != nil {
+		return fmt.Errorf("PagerDutyペイロード生成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pagerDutyEventsURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("PagerDutyリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("PagerDuty送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("PagerDuty送信失敗: ステータスコード %d, レスポンス: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+func pagerDutySeverity(kind EventKind) string {
+	switch kind {
+	case EventRestartFailed:
+		return "critical"
+	case EventRestartTriggered:
+		return "warning"
+	default:
+		return "error"
+	}
+}
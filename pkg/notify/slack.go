@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SlackNotifier はSlack Incoming Webhook(Block Kit)向けのNotifierです
+type SlackNotifier struct {
+	WebhookURL string
+	Enabled    bool
+	httpClient *http.Client
+}
+
+// NewSlackNotifier は新しいSlackNotifierを作成します
+func NewSlackNotifier(webhookURL string, enabled bool) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL: webhookURL,
+		Enabled:    enabled,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name はNotifierインターフェースの実装です
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+type slackBlockMessage struct {
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type   string       `json:"type"`
+	Text   *slackText   `json:"text,omitempty"`
+	Fields []*slackText `json:"fields,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Notify はNotifierインターフェースの実装です
+func (n *SlackNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	if !n.Enabled || n.WebhookURL == "" {
+		return nil
+	}
+
+	headerText := fmt.Sprintf(":warning: Node `%s` is %s (%s)", event.NodeName, event.Status, event.Duration)
+	if event.Kind == EventRestartTriggered {
+		headerText = fmt.Sprintf(":arrows_counterclockwise: Restarting node `%s`", event.NodeName)
+	} else if event.Kind == EventRestartFailed {
+		headerText = fmt.Sprintf(":rotating_light: Restart of node `%s` failed or did not recover", event.NodeName)
+	} else if event.Kind == EventRecovered {
+		headerText = fmt.Sprintf(":white_check_mark: Node `%s` recovered", event.NodeName)
+	}
+
+	message := slackBlockMessage{
+		Blocks: []slackBlock{
+			{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: headerText},
+			},
+			{
+				Type: "section",
+				Fields: []*slackText{
+					{Type: "mrkdwn", Text: fmt.Sprintf("*IP:*\n%s", event.IP)},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*VM Info:*\n%s", orDash(event.VMInfo))},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("Slackペイロード生成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.WebhookURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("Slackリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Slack送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Slack送信失敗: ステータスコード %d, レスポンス: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TeamsNotifier はMicrosoft Teams Incoming Webhook(MessageCard形式)向けのNotifierです
+type TeamsNotifier struct {
+	WebhookURL string
+	Enabled    bool
+	httpClient *http.Client
+}
+
+// NewTeamsNotifier は新しいTeamsNotifierを作成します
+func NewTeamsNotifier(webhookURL string, enabled bool) *TeamsNotifier {
+	return &TeamsNotifier{
+		WebhookURL: webhookURL,
+		Enabled:    enabled,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name はNotifierインターフェースの実装です
+func (n *TeamsNotifier) Name() string {
+	return "teams"
+}
+
+type teamsMessageCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	ActivityTitle string             `json:"activityTitle"`
+	Facts         []teamsSectionFact `json:"facts"`
+}
+
+type teamsSectionFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Notify はNotifierインターフェースの実装です
+func (n *TeamsNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	if !n.Enabled || n.WebhookURL == "" {
+		return nil
+	}
+
+	themeColor := "FFA500" // オレンジ(NotReady)
+	switch event.Kind {
+	case EventRestartFailed:
+		themeColor = "FF0000" // 赤
+	case EventRecovered:
+		themeColor = "00FF00" // 緑
+	case EventRestartTriggered:
+		themeColor = "FFFF00" // 黄
+	}
+
+	card := teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: themeColor,
+		Summary:    fmt.Sprintf("Kubernetes Node %s: %s", event.NodeName, event.Kind),
+		Sections: []teamsSection{
+			{
+				ActivityTitle: fmt.Sprintf("Node `%s` is %s (for %s)", event.NodeName, event.Status, event.Duration),
+				Facts: []teamsSectionFact{
+					{Name: "IP", Value: event.IP},
+					{Name: "VM Info", Value: orDash(event.VMInfo)},
+					{Name: "Event", Value: string(event.Kind)},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("Teamsペイロード生成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.WebhookURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("Teamsリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Teams送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Teams送信失敗: ステータスコード %d, レスポンス: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
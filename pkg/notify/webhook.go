@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WebhookNotifier はNodeEventをそのままJSONとしてPOSTする汎用Webhookです
+// 他システムへの取り込み用に、決め打ちのフォーマットを持たないシンクとして提供します
+type WebhookNotifier struct {
+	URL        string
+	Enabled    bool
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier は新しいWebhookNotifierを作成します
+func NewWebhookNotifier(url string, enabled bool) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        url,
+		Enabled:    enabled,
+		httpClient: &http.Client{},
+	}
+}
+
+// Name はNotifierインターフェースの実装です
+func (n *WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+// Notify はNotifierインターフェースの実装です
+func (n *WebhookNotifier) Notify(ctx context.Context, event NodeEvent) error {
+	if !n.Enabled || n.URL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("Webhookペイロード生成エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("Webhookリクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Webhook送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Webhook送信失敗: ステータスコード %d, レスポンス: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
@@ -4,16 +4,43 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
+
 	"github.com/xpadev/k8s-node-monitor/pkg/config"
+	"github.com/xpadev/k8s-node-monitor/pkg/metrics"
+)
+
+// taskPollInterval/taskPollTimeout はUPIDタスクの完了をポーリングする間隔と上限時間です
+const (
+	taskPollInterval = 2 * time.Second
+	taskPollTimeout  = 2 * time.Minute
+)
+
+// ticketTTL はPVEAuthCookieの想定有効期限です。Proxmoxの実際の有効期限(2時間)より
+// 余裕を持たせています。ticketRefreshMargin はその手前で能動的に再ログインする余白です
+const (
+	ticketTTL           = 90 * time.Minute
+	ticketRefreshMargin = 10 * time.Minute
 )
 
+// backoff はProxmox API呼び出し全体(ログイン〜レスポンス解析)をリトライするためのバックオフ設定です
+// 5xx/ネットワークエラー/401(チケット失効)のみリトライし、それ以外の4xxは呼び出し元へ即座に返します
+var backoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
 // Client はProxmox VE APIクライアント
 type Client struct {
 	apiURL      string
@@ -22,8 +49,15 @@ type Client struct {
 	tokenID     string
 	tokenSecret string
 	httpClient  *http.Client
-	ticket      string
-	csrfToken   string
+
+	ticketMu     sync.RWMutex
+	ticket       string
+	csrfToken    string
+	ticketExpiry time.Time
+
+	refreshOnce sync.Once
+	stopOnce    sync.Once
+	stopRefresh chan struct{}
 }
 
 // NewClient は新しいProxmox APIクライアントを作成します
@@ -45,16 +79,122 @@ func NewClient(config *config.ProxmoxConfig) *Client {
 		tokenID:     config.TokenID,
 		tokenSecret: config.TokenSecret,
 		httpClient:  httpClient,
+		stopRefresh: make(chan struct{}),
+	}
+}
+
+// Close はバックグラウンドのチケット自動更新goroutineを停止します
+func (c *Client) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stopRefresh)
+	})
+}
+
+// apiError はProxmox APIが返した非2xxレスポンスです。ステータスコードでリトライ可否を判定するために使います
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("ステータスコード %d, レスポンス: %s", e.StatusCode, e.Body)
+}
+
+// networkError はHTTPリクエストそのものが失敗したこと(タイムアウト、接続失敗など)を表します
+// レスポンスボディのデコード失敗や認証情報の誤りのような、リトライしても結果が変わらない
+// エラーと区別するために使います
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return e.err.Error() }
+func (e *networkError) Unwrap() error { return e.err }
+
+// isRetryable はerrが5xx、401(チケット失効によるものとして再ログイン後にリトライ可能)、
+// またはnetworkError(タイムアウト、接続失敗など)であるかを返します
+// それ以外の4xxやJSONデコードエラーはリトライしても解消しないためリトライしません
+func isRetryable(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusUnauthorized
+	}
+
+	var netErr *networkError
+	return errors.As(err, &netErr)
+}
+
+// withBackoff はfnを指数バックオフ付きでリトライします。リトライ不能なエラーは即座に返します
+func withBackoff(fn func() error) error {
+	var lastErr error
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		lastErr = fn()
+		if lastErr == nil {
+			return true, nil
+		}
+		if !isRetryable(lastErr) {
+			return false, lastErr
+		}
+		return false, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return lastErr
+	}
+	return err
+}
+
+// doRequest はProxmox APIへのHTTPリクエストを実行し、レイテンシと結果をmetricsへ記録します
+func (c *Client) doRequest(action string, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	metrics.ProxmoxAPIRequestDurationSeconds.WithLabelValues(action).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if resp != nil {
+		code = fmt.Sprintf("%d", resp.StatusCode)
+	}
+	metrics.ProxmoxAPIRequestsTotal.WithLabelValues(action, code).Inc()
+
+	return resp, err
+}
+
+// ticketValid はキャッシュ済みのチケットがまだ有効かどうかを返します
+// トークン認証モードでは常にtrue(ログイン不要)です
+func (c *Client) ticketValid() bool {
+	if c.tokenID != "" && c.tokenSecret != "" {
+		return true
 	}
+
+	c.ticketMu.RLock()
+	defer c.ticketMu.RUnlock()
+	return !c.ticketExpiry.IsZero() && time.Now().Before(c.ticketExpiry)
 }
 
-// Login はProxmox APIにログインします
+// invalidateTicket はキャッシュ済みのチケットを破棄し、次回のLoginで再ログインさせます
+// 401レスポンスを受け取った際に呼びます
+func (c *Client) invalidateTicket() {
+	c.ticketMu.Lock()
+	defer c.ticketMu.Unlock()
+	c.ticketExpiry = time.Time{}
+}
+
+// Login はキャッシュ済みのチケットが有効であれば何もせず、そうでなければ
+// Proxmox APIに再ログインしてチケットを取得・キャッシュします
 func (c *Client) Login() error {
 	// APIトークンがある場合はログイン不要
 	if c.tokenID != "" && c.tokenSecret != "" {
 		return nil
 	}
 
+	if c.ticketValid() {
+		return nil
+	}
+
+	return c.relogin()
+}
+
+// relogin は無条件にProxmox APIへログインし直し、チケットをキャッシュします
+// 成功した最初のログインでバックグラウンドの自動更新goroutineを起動します
+func (c *Client) relogin() error {
 	data := url.Values{}
 	data.Set("username", c.username)
 	data.Set("password", c.password)
@@ -65,22 +205,22 @@ func (c *Client) Login() error {
 	}
 	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doRequest("login", req)
 	if err != nil {
-		return err
+		return &networkError{err: err}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ログイン失敗: ステータスコード %d", resp.StatusCode)
-	}
-
 	// io/ioutil は非推奨なので io.ReadAll を使用
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
 	var result struct {
 		Data struct {
 			Ticket    string `json:"ticket"`
@@ -91,72 +231,102 @@ func (c *Client) Login() error {
 		return err
 	}
 
+	c.ticketMu.Lock()
 	c.ticket = result.Data.Ticket
 	c.csrfToken = result.Data.CSRFToken
+	c.ticketExpiry = time.Now().Add(ticketTTL)
+	c.ticketMu.Unlock()
+
+	c.refreshOnce.Do(func() { go c.runAutoRefresh() })
 	return nil
 }
 
+// runAutoRefresh はticketTTL-ticketRefreshMarginごとにチケットを能動的に更新し続けます
+// Closeが呼ばれるまで動作します
+func (c *Client) runAutoRefresh() {
+	ticker := time.NewTicker(ticketTTL - ticketRefreshMargin)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopRefresh:
+			return
+		case <-ticker.C:
+			if err := c.relogin(); err != nil {
+				fmt.Printf("Proxmoxチケットの自動更新に失敗しました: %v\n", err)
+			}
+		}
+	}
+}
+
 // VMStatus はVMの状態情報
 type VMStatus struct {
 	Status string `json:"status"` // running, stopped など
 }
 
-// GetVMStatus はVMの現在の状態を取得します
+// GetVMStatus はVMの現在の状態を取得します。5xx/ネットワークエラー/チケット失効は自動的にリトライします
 func (c *Client) GetVMStatus(node string, vmID int) (string, error) {
-	// まずログインを試みる
+	var status string
+	err := withBackoff(func() error {
+		s, err := c.getVMStatusOnce(node, vmID)
+		if err != nil {
+			return err
+		}
+		status = s
+		return nil
+	})
+	return status, err
+}
+
+func (c *Client) getVMStatusOnce(node string, vmID int) (string, error) {
 	if err := c.Login(); err != nil {
-		return "", fmt.Errorf("ログインエラー: %v", err)
+		return "", fmt.Errorf("ログインエラー: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/nodes/%s/qemu/%d/status/current", c.apiURL, node, vmID)
-	
-	// リクエスト作成
-	req, err := http.NewRequest("GET", url, nil)
+	requestURL := fmt.Sprintf("%s/nodes/%s/qemu/%d/status/current", c.apiURL, node, vmID)
+
+	req, err := http.NewRequest("GET", requestURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("HTTPリクエスト作成エラー: %v", err)
 	}
-	
-	// 認証情報を設定
-	addAuthHeaders(req, c)
-	
-	// リクエスト送信
-	resp, err := c.httpClient.Do(req)
+	c.addAuthHeaders(req)
+
+	resp, err := c.doRequest("get_vm_status", req)
 	if err != nil {
-		return "", fmt.Errorf("HTTPリクエストエラー: %v", err)
+		return "", &networkError{err: fmt.Errorf("HTTPリクエストエラー: %w", err)}
 	}
 	defer resp.Body.Close()
-	
-	// エラーチェック
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("VM状態取得失敗: ステータスコード %d, レスポンス: %s", resp.StatusCode, string(bodyBytes))
-	}
-	
-	// レスポンス解析
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("レスポンス読み込みエラー: %v", err)
 	}
-	
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.invalidateTicket()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
 	var result struct {
 		Data VMStatus `json:"data"`
 	}
-	
 	if err := json.Unmarshal(body, &result); err != nil {
 		return "", fmt.Errorf("JSONデコードエラー: %v", err)
 	}
-	
+
 	return result.Data.Status, nil
 }
 
-// RestartVM はVMを再起動します
+// RestartVM はVMを再起動し、Proxmoxのタスクが完了するまで待ち合わせます
 func (c *Client) RestartVM(node string, vmID int) error {
 	// まずVM状態を取得
 	status, err := c.GetVMStatus(node, vmID)
 	if err != nil {
 		return fmt.Errorf("VM状態取得エラー: %v", err)
 	}
-	
+
 	// VMの状態に応じて処理を分岐
 	switch status {
 	case "stopped":
@@ -181,57 +351,160 @@ func (c *Client) resetVM(node string, vmID int) error {
 	return c.vmAction(node, vmID, "reset", "リセット")
 }
 
-// vmAction はVMに対して指定されたアクションを実行します
+// vmAction はVMに対して指定されたアクションを実行し、返ってきたUPIDのタスクが
+// 完了する(status=stopped, exitstatus=OK)までポーリングします
 func (c *Client) vmAction(node string, vmID int, action, actionName string) error {
-	// まずログインを試みる
+	var upid string
+	err := withBackoff(func() error {
+		u, err := c.vmActionOnce(node, vmID, action, actionName)
+		if err != nil {
+			return err
+		}
+		upid = u
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.waitForTask(node, upid); err != nil {
+		return fmt.Errorf("VM%sタスクの完了待ちに失敗: %v", actionName, err)
+	}
+	return nil
+}
+
+func (c *Client) vmActionOnce(node string, vmID int, action, actionName string) (string, error) {
 	if err := c.Login(); err != nil {
-		return fmt.Errorf("ログインエラー: %v", err)
+		return "", fmt.Errorf("ログインエラー: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/nodes/%s/qemu/%d/status/%s", c.apiURL, node, vmID, action)
-	
+	requestURL := fmt.Sprintf("%s/nodes/%s/qemu/%d/status/%s", c.apiURL, node, vmID, action)
+
 	// POSTリクエスト用のJSONデータ
 	data := map[string]string{}
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("JSONエンコードエラー: %v", err)
+		return "", fmt.Errorf("JSONエンコードエラー: %v", err)
 	}
-	
-	// リクエスト作成
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+
+	req, err := http.NewRequest("POST", requestURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("HTTPリクエスト作成エラー: %v", err)
+		return "", fmt.Errorf("HTTPリクエスト作成エラー: %v", err)
 	}
-	
-	// 認証情報を設定
-	addAuthHeaders(req, c)
+	c.addAuthHeaders(req)
 	req.Header.Add("Content-Type", "application/json")
-	
-	// リクエスト送信
-	resp, err := c.httpClient.Do(req)
+
+	resp, err := c.doRequest("vm_action:"+action, req)
 	if err != nil {
-		return fmt.Errorf("HTTPリクエストエラー: %v", err)
+		return "", &networkError{err: fmt.Errorf("HTTPリクエストエラー: %w", err)}
 	}
 	defer resp.Body.Close()
-	
-	// エラーチェック
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("レスポンス読み込みエラー: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.invalidateTicket()
+	}
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("VM%s失敗: ステータスコード %d, レスポンス: %s", actionName, resp.StatusCode, string(bodyBytes))
+		return "", &apiError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
-	
-	return nil
+
+	// Proxmoxはこのエンドポイントに対して非同期タスクのUPIDを文字列で返す
+	var result struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("JSONデコードエラー: %v", err)
+	}
+
+	return result.Data, nil
+}
+
+// taskStatus はタスクステータスAPIのレスポンスです
+type taskStatus struct {
+	Status     string `json:"status"`     // running, stopped
+	ExitStatus string `json:"exitstatus"` // OK など。完了するまでは空
+}
+
+// waitForTask はUPIDで示されるタスクがstatus=stoppedになるまでポーリングし、
+// exitstatusがOK以外であればエラーを返します
+func (c *Client) waitForTask(node, upid string) error {
+	return wait.PollImmediate(taskPollInterval, taskPollTimeout, func() (bool, error) {
+		status, err := c.getTaskStatus(node, upid)
+		if err != nil {
+			// ポーリング中の一時的なエラーはタイムアウトまでリトライする
+			return false, nil
+		}
+
+		if status.Status != "stopped" {
+			return false, nil
+		}
+
+		if status.ExitStatus != "OK" {
+			return false, fmt.Errorf("タスクが異常終了しました: %s", status.ExitStatus)
+		}
+
+		return true, nil
+	})
+}
+
+func (c *Client) getTaskStatus(node, upid string) (*taskStatus, error) {
+	if err := c.Login(); err != nil {
+		return nil, fmt.Errorf("ログインエラー: %w", err)
+	}
+
+	requestURL := fmt.Sprintf("%s/nodes/%s/tasks/%s/status", c.apiURL, node, url.PathEscape(upid))
+
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエスト作成エラー: %v", err)
+	}
+	c.addAuthHeaders(req)
+
+	resp, err := c.doRequest("get_task_status", req)
+	if err != nil {
+		return nil, &networkError{err: fmt.Errorf("HTTPリクエストエラー: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("レスポンス読み込みエラー: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.invalidateTicket()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &apiError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result struct {
+		Data taskStatus `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("JSONデコードエラー: %v", err)
+	}
+
+	return &result.Data, nil
 }
 
 // addAuthHeaders は認証ヘッダーをリクエストに追加します
-func addAuthHeaders(req *http.Request, c *Client) {
+func (c *Client) addAuthHeaders(req *http.Request) {
 	if c.tokenID != "" && c.tokenSecret != "" {
 		// APIトークンを使用
 		req.Header.Add("Authorization", fmt.Sprintf("PVEAPIToken=%s=%s", c.tokenID, c.tokenSecret))
 		return
 	}
-	
+
 	// チケット認証を使用
-	req.Header.Add("Cookie", fmt.Sprintf("PVEAuthCookie=%s", c.ticket))
-	req.Header.Add("CSRFPreventionToken", c.csrfToken)
+	c.ticketMu.RLock()
+	ticket, csrfToken := c.ticket, c.csrfToken
+	c.ticketMu.RUnlock()
+
+	req.Header.Add("Cookie", fmt.Sprintf("PVEAuthCookie=%s", ticket))
+	req.Header.Add("CSRFPreventionToken", csrfToken)
 }